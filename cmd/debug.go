@@ -0,0 +1,38 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/aneurysm9/challenger/vm"
+	"github.com/aneurysm9/challenger/vm/debug"
+	"github.com/spf13/cobra"
+)
+
+var debugCmd = &cobra.Command{
+	Use:   "debug [image]",
+	Short: "Load an image and drop into an interactive debugger",
+	Args:  cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		image := "challenge.bin"
+		if len(args) > 0 {
+			image = args[0]
+		}
+
+		machine, err := vm.LoadImage(image)
+		if err != nil {
+			fmt.Printf("Error loading image: %s", err)
+			os.Exit(1)
+		}
+
+		d := debug.NewDebugger(machine)
+		if err := d.REPL(os.Stdin, os.Stdout); err != nil {
+			fmt.Printf("Error running debugger: %s", err)
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(debugCmd)
+}
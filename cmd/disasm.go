@@ -0,0 +1,47 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/aneurysm9/challenger/vm"
+	"github.com/aneurysm9/challenger/vm/disasm"
+	"github.com/spf13/cobra"
+)
+
+var disasmDot string
+
+var disasmCmd = &cobra.Command{
+	Use:   "disasm [image]",
+	Short: "Print a disassembly listing of an image",
+	Args:  cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		image := "challenge.bin"
+		if len(args) > 0 {
+			image = args[0]
+		}
+
+		machine, err := vm.LoadImage(image)
+		if err != nil {
+			fmt.Printf("Error loading image: %s", err)
+			os.Exit(1)
+		}
+
+		instrs := disasm.Disassemble(machine, 0, uint16(machine.MemorySize()))
+		for _, instr := range instrs {
+			fmt.Println(instr)
+		}
+
+		if disasmDot != "" {
+			if err := os.WriteFile(disasmDot, []byte(disasm.CFG(instrs)), 0644); err != nil {
+				fmt.Printf("Error writing CFG: %s", err)
+				os.Exit(1)
+			}
+		}
+	},
+}
+
+func init() {
+	disasmCmd.Flags().StringVar(&disasmDot, "dot", "", "write a control-flow graph in DOT format to this file")
+	rootCmd.AddCommand(disasmCmd)
+}
@@ -0,0 +1,38 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/aneurysm9/challenger/vm"
+	"github.com/spf13/cobra"
+)
+
+var loadCmd = &cobra.Command{
+	Use:   "load [file]",
+	Short: "Restore a snapshot and resume running it",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		f, err := os.Open(args[0])
+		if err != nil {
+			fmt.Printf("Error opening snapshot: %s", err)
+			os.Exit(1)
+		}
+		defer f.Close()
+
+		machine := vm.NewMachine()
+		if err := machine.Restore(f); err != nil {
+			fmt.Printf("Error restoring snapshot: %s", err)
+			os.Exit(1)
+		}
+
+		if err := machine.Run(); err != nil && err != vm.ErrorHalt {
+			fmt.Printf("Error running machine: %s", err)
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(loadCmd)
+}
@@ -0,0 +1,49 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/aneurysm9/challenger/vm"
+	"github.com/aneurysm9/challenger/vm/record"
+	"github.com/spf13/cobra"
+)
+
+var recordCmd = &cobra.Command{
+	Use:   "record [image] [transcript]",
+	Short: "Run an image interactively, recording input to transcript",
+	Args:  cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		machine, err := vm.LoadImage(args[0])
+		if err != nil {
+			fmt.Printf("Error loading image: %s", err)
+			os.Exit(1)
+		}
+
+		rec := record.NewRecorder(os.Stdin, machine)
+		machine.In = rec
+
+		runErr := machine.Run()
+
+		f, err := os.Create(args[1])
+		if err != nil {
+			fmt.Printf("Error creating transcript: %s", err)
+			os.Exit(1)
+		}
+		defer f.Close()
+
+		if err := rec.Save(f); err != nil {
+			fmt.Printf("Error saving transcript: %s", err)
+			os.Exit(1)
+		}
+
+		if runErr != nil && runErr != vm.ErrorHalt {
+			fmt.Printf("Error running machine: %s", runErr)
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(recordCmd)
+}
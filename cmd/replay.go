@@ -0,0 +1,46 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/aneurysm9/challenger/vm"
+	"github.com/aneurysm9/challenger/vm/record"
+	"github.com/spf13/cobra"
+)
+
+var replayCmd = &cobra.Command{
+	Use:   "replay [image] [transcript]",
+	Short: "Run an image, feeding it a previously recorded transcript",
+	Args:  cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		machine, err := vm.LoadImage(args[0])
+		if err != nil {
+			fmt.Printf("Error loading image: %s", err)
+			os.Exit(1)
+		}
+
+		f, err := os.Open(args[1])
+		if err != nil {
+			fmt.Printf("Error opening transcript: %s", err)
+			os.Exit(1)
+		}
+		defer f.Close()
+
+		rep, err := record.Load(f)
+		if err != nil {
+			fmt.Printf("Error loading transcript: %s", err)
+			os.Exit(1)
+		}
+		machine.In = rep
+
+		if err := machine.Run(); err != nil && err != vm.ErrorHalt {
+			fmt.Printf("Error running machine: %s", err)
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(replayCmd)
+}
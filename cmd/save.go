@@ -0,0 +1,40 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/aneurysm9/challenger/vm"
+	"github.com/spf13/cobra"
+)
+
+var saveCmd = &cobra.Command{
+	Use:   "save [image] [file]",
+	Short: "Snapshot a freshly loaded image to file",
+	Long: "Save loads an image and immediately writes a snapshot of its " +
+		"initial state to file. To checkpoint mid-run, use the debugger's " +
+		"own save command instead.",
+	Args: cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		machine, err := vm.LoadImage(args[0])
+		if err != nil {
+			fmt.Printf("Error loading image: %s", err)
+			os.Exit(1)
+		}
+
+		data, err := machine.Snapshot()
+		if err != nil {
+			fmt.Printf("Error creating snapshot: %s", err)
+			os.Exit(1)
+		}
+
+		if err := os.WriteFile(args[1], data, 0644); err != nil {
+			fmt.Printf("Error writing snapshot: %s", err)
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(saveCmd)
+}
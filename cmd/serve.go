@@ -0,0 +1,53 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/aneurysm9/challenger/vm"
+	"github.com/aneurysm9/challenger/vm/debug"
+	"github.com/aneurysm9/challenger/vm/rpc"
+	"github.com/spf13/cobra"
+)
+
+var serveListen string
+var serveSocket string
+
+var serveCmd = &cobra.Command{
+	Use:   "serve [image]",
+	Short: "Load an image and expose it over a JSON-RPC control server",
+	Args:  cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		image := "challenge.bin"
+		if len(args) > 0 {
+			image = args[0]
+		}
+
+		machine, err := vm.LoadImage(image)
+		if err != nil {
+			fmt.Printf("Error loading image: %s", err)
+			os.Exit(1)
+		}
+
+		d := debug.NewDebugger(machine)
+
+		if serveSocket != "" {
+			if err := rpc.Serve(d, "unix", serveSocket); err != nil {
+				fmt.Printf("Error serving: %s", err)
+				os.Exit(1)
+			}
+			return
+		}
+
+		if err := rpc.Serve(d, "tcp", serveListen); err != nil {
+			fmt.Printf("Error serving: %s", err)
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	serveCmd.Flags().StringVar(&serveListen, "listen", ":7000", "TCP address to listen on")
+	serveCmd.Flags().StringVar(&serveSocket, "socket", "", "Unix socket to listen on, instead of TCP")
+	rootCmd.AddCommand(serveCmd)
+}
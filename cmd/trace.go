@@ -0,0 +1,107 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/aneurysm9/challenger/vm"
+	"github.com/aneurysm9/challenger/vm/disasm"
+	"github.com/aneurysm9/challenger/vm/trace"
+	"github.com/spf13/cobra"
+)
+
+var traceCmd = &cobra.Command{
+	Use:   "trace",
+	Short: "Record and analyze execution traces",
+}
+
+var traceRunCmd = &cobra.Command{
+	Use:   "run [image] [tracefile]",
+	Short: "Run an image, writing a structured execution trace to tracefile",
+	Args:  cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		machine, err := vm.LoadImage(args[0])
+		if err != nil {
+			fmt.Printf("Error loading image: %s", err)
+			os.Exit(1)
+		}
+
+		f, err := os.Create(args[1])
+		if err != nil {
+			fmt.Printf("Error creating trace file: %s", err)
+			os.Exit(1)
+		}
+		defer f.Close()
+
+		trace.Attach(machine, f)
+
+		if err := machine.Run(); err != nil && err != vm.ErrorHalt {
+			fmt.Printf("Error running machine: %s", err)
+			os.Exit(1)
+		}
+	},
+}
+
+var traceAnalyzeCmd = &cobra.Command{
+	Use:   "analyze [tracefile]",
+	Short: "Summarize a trace previously captured with trace run",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		f, err := os.Open(args[0])
+		if err != nil {
+			fmt.Printf("Error opening trace file: %s", err)
+			os.Exit(1)
+		}
+		defer f.Close()
+
+		stats, err := trace.Analyze(f)
+		if err != nil {
+			fmt.Printf("Error analyzing trace: %s", err)
+			os.Exit(1)
+		}
+
+		fmt.Println("opcode frequency:")
+		for _, op := range sortedKeys(stats.OpcodeCounts) {
+			fmt.Printf("  %-6s %d\n", disasm.Mnemonic(op), stats.OpcodeCounts[op])
+		}
+
+		fmt.Println("hot addresses:")
+		for _, addr := range topAddresses(stats.AddressCounts, 10) {
+			fmt.Printf("  %05d: %d\n", addr, stats.AddressCounts[addr])
+		}
+
+		fmt.Println("call edges:")
+		for edge, count := range stats.CallEdges {
+			fmt.Printf("  %05d -> %05d: %d\n", edge.From, edge.To, count)
+		}
+
+		fmt.Println("per-function instruction counts:")
+		for _, fn := range sortedKeys(stats.FunctionInstrs) {
+			fmt.Printf("  %05d: %d\n", fn, stats.FunctionInstrs[fn])
+		}
+	},
+}
+
+func sortedKeys(m map[uint16]int) []uint16 {
+	keys := make([]uint16, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i] < keys[j] })
+	return keys
+}
+
+func topAddresses(counts map[uint16]int, n int) []uint16 {
+	addrs := sortedKeys(counts)
+	sort.Slice(addrs, func(i, j int) bool { return counts[addrs[i]] > counts[addrs[j]] })
+	if len(addrs) > n {
+		addrs = addrs[:n]
+	}
+	return addrs
+}
+
+func init() {
+	traceCmd.AddCommand(traceRunCmd, traceAnalyzeCmd)
+	rootCmd.AddCommand(traceCmd)
+}
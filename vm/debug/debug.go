@@ -0,0 +1,181 @@
+// Package debug provides an interactive debugger for a vm.Machine, with
+// breakpoints, single-stepping, step-over, and state inspection.
+package debug
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/aneurysm9/challenger/vm"
+)
+
+// errPause is returned internally from the machine's PreStep hook to unwind
+// Run when a breakpoint is hit. It never escapes the Debugger.
+var errPause = fmt.Errorf("debugger: paused")
+
+const callOpcode = 17
+
+// Debugger wraps a vm.Machine, adding breakpoints and stepping on top of its
+// execution loop.
+type Debugger struct {
+	Machine *vm.Machine
+
+	breakpoints map[uint16]bool
+}
+
+// NewDebugger creates a Debugger around m, installing its step hooks. The
+// machine should not be run directly once it is under debugger control.
+func NewDebugger(m *vm.Machine) *Debugger {
+	d := &Debugger{
+		Machine:     m,
+		breakpoints: make(map[uint16]bool),
+	}
+	m.PreStep = d.preStep
+	return d
+}
+
+func (d *Debugger) preStep(m *vm.Machine) error {
+	if d.breakpoints[m.IP()] {
+		return errPause
+	}
+	return nil
+}
+
+// SetBreakpoint sets a breakpoint at addr.
+func (d *Debugger) SetBreakpoint(addr uint16) {
+	d.breakpoints[addr] = true
+}
+
+// ClearBreakpoint clears a breakpoint at addr.
+func (d *Debugger) ClearBreakpoint(addr uint16) {
+	delete(d.breakpoints, addr)
+}
+
+// Breakpoints returns the addresses of all set breakpoints.
+func (d *Debugger) Breakpoints() []uint16 {
+	addrs := make([]uint16, 0, len(d.breakpoints))
+	for addr := range d.breakpoints {
+		addrs = append(addrs, addr)
+	}
+	return addrs
+}
+
+// Step executes a single instruction, ignoring breakpoints. This matters
+// when the machine is already stopped on one: Machine.Step() would consult
+// d.preStep again and immediately re-pause without executing anything, so
+// Step bypasses the hook for the duration of the call.
+func (d *Debugger) Step() error {
+	hook := d.Machine.PreStep
+	d.Machine.PreStep = nil
+	defer func() { d.Machine.PreStep = hook }()
+	return d.Machine.Step()
+}
+
+// StepOver executes a single instruction, but if it is a call, runs until
+// the call returns (or a breakpoint is hit) rather than stepping into it.
+func (d *Debugger) StepOver() error {
+	if d.Machine.ReadMemory(d.Machine.IP()) != callOpcode {
+		return d.Step()
+	}
+
+	ret := d.Machine.IP() + 2
+	depth := len(d.Machine.CallStack())
+
+	// Execute the call itself with Step, which bypasses breakpoints, so
+	// that a breakpoint sitting on the call instruction doesn't just
+	// re-pause here without entering the call.
+	if err := d.Step(); err != nil {
+		return err
+	}
+
+	temp := !d.breakpoints[ret]
+	if temp {
+		d.SetBreakpoint(ret)
+		defer d.ClearBreakpoint(ret)
+	}
+
+	for {
+		if err := d.Continue(); err != nil {
+			return err
+		}
+		if d.Machine.IP() == ret && len(d.Machine.CallStack()) <= depth {
+			return nil
+		}
+		if temp {
+			// We stopped at the temporary breakpoint but not yet back at
+			// our own frame (e.g. recursion); keep going.
+			continue
+		}
+		// We stopped at a breakpoint the caller set; surface it.
+		return nil
+	}
+}
+
+// Continue runs the machine until it halts, errors, or hits a breakpoint.
+// Hitting a breakpoint is not treated as an error.
+func (d *Debugger) Continue() error {
+	if d.breakpoints[d.Machine.IP()] {
+		// The machine is already stopped on a breakpoint (the usual state
+		// right after a previous Continue, or a breakpoint set on the entry
+		// point before the first one); Run's first Step would consult
+		// d.preStep and immediately return errPause again without executing
+		// anything, so step off it with Step, which bypasses the hook.
+		if err := d.Step(); err != nil {
+			return err
+		}
+	}
+	if err := d.Machine.Run(); err != nil && err != errPause {
+		return err
+	}
+	return nil
+}
+
+// Backtrace returns the current call stack, outermost frame first.
+func (d *Debugger) Backtrace() []uint16 {
+	return d.Machine.CallStack()
+}
+
+// ReadRegister reads register idx.
+func (d *Debugger) ReadRegister(idx uint16) (uint16, error) {
+	return d.Machine.Register(idx)
+}
+
+// WriteRegister writes register idx.
+func (d *Debugger) WriteRegister(idx, val uint16) error {
+	return d.Machine.SetRegister(idx, val)
+}
+
+// ReadMemory reads count words of memory starting at addr.
+func (d *Debugger) ReadMemory(addr, count uint16) []uint16 {
+	vals := make([]uint16, 0, count)
+	for i := uint16(0); i < count; i++ {
+		vals = append(vals, d.Machine.ReadMemory(addr+i))
+	}
+	return vals
+}
+
+// WriteMemory patches a single word of memory at addr.
+func (d *Debugger) WriteMemory(addr, val uint16) {
+	d.Machine.WriteMemory(addr, val)
+}
+
+// Save snapshots the machine's current state to file, so a risky puzzle
+// branch can be rewound later with Load.
+func (d *Debugger) Save(file string) error {
+	data, err := d.Machine.Snapshot()
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(file, data, 0644)
+}
+
+// Load restores the machine's state from a snapshot previously written by
+// Save.
+func (d *Debugger) Load(file string) error {
+	f, err := os.Open(file)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	return d.Machine.Restore(f)
+}
@@ -0,0 +1,97 @@
+package debug
+
+import (
+	"testing"
+
+	"github.com/aneurysm9/challenger/vm"
+)
+
+// callProgram is:
+//
+//	0: call 4   ; 17, 4
+//	2: halt     ; 0
+//	4: noop     ; 21
+//	5: ret      ; 18
+func callProgram() *Debugger {
+	m := vm.NewMachine()
+	words := []uint16{17, 4, 0, 0, 21, 18}
+	for addr, w := range words {
+		m.WriteMemory(uint16(addr), w)
+	}
+	return NewDebugger(m)
+}
+
+func TestContinuePastBreakpoint(t *testing.T) {
+	d := callProgram()
+	d.SetBreakpoint(4)
+
+	if err := d.Continue(); err != nil {
+		t.Fatalf("Continue (to breakpoint): %v", err)
+	}
+	if ip := d.Machine.IP(); ip != 4 {
+		t.Fatalf("IP = %d, want 4 (stopped at breakpoint)", ip)
+	}
+
+	// Regression: a second Continue from a machine already stopped on a
+	// breakpoint used to immediately re-pause without executing anything,
+	// leaving the machine stuck there instead of running to completion.
+	if err := d.Continue(); err != vm.ErrorHalt {
+		t.Fatalf("Continue (past breakpoint) = %v, want ErrorHalt", err)
+	}
+}
+
+func TestStepOverSkipsCall(t *testing.T) {
+	d := callProgram()
+
+	if err := d.StepOver(); err != nil {
+		t.Fatalf("StepOver: %v", err)
+	}
+	if ip := d.Machine.IP(); ip != 2 {
+		t.Errorf("IP = %d, want 2 (returned from call)", ip)
+	}
+	if depth := len(d.Machine.CallStack()); depth != 0 {
+		t.Errorf("CallStack depth = %d, want 0", depth)
+	}
+
+	if err := d.Step(); err != vm.ErrorHalt {
+		t.Fatalf("Step (halt) = %v, want ErrorHalt", err)
+	}
+}
+
+func TestStepEntersCall(t *testing.T) {
+	d := callProgram()
+
+	if err := d.Step(); err != nil {
+		t.Fatalf("Step: %v", err)
+	}
+	if ip := d.Machine.IP(); ip != 4 {
+		t.Errorf("IP = %d, want 4 (entered call)", ip)
+	}
+	if depth := len(d.Machine.CallStack()); depth != 1 {
+		t.Errorf("CallStack depth = %d, want 1", depth)
+	}
+}
+
+func TestRegisterReadWrite(t *testing.T) {
+	d := callProgram()
+
+	if err := d.WriteRegister(0, 42); err != nil {
+		t.Fatalf("WriteRegister: %v", err)
+	}
+	val, err := d.ReadRegister(0)
+	if err != nil {
+		t.Fatalf("ReadRegister: %v", err)
+	}
+	if val != 42 {
+		t.Errorf("ReadRegister(0) = %d, want 42", val)
+	}
+}
+
+func TestMemoryReadWrite(t *testing.T) {
+	d := callProgram()
+
+	d.WriteMemory(2, 99)
+	if got := d.ReadMemory(2, 1); len(got) != 1 || got[0] != 99 {
+		t.Errorf("ReadMemory(2, 1) = %v, want [99]", got)
+	}
+}
@@ -0,0 +1,148 @@
+package debug
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/aneurysm9/challenger/vm"
+)
+
+// REPL reads debugger commands from in and writes output to out until the
+// machine halts, errors, or the user quits.
+func (d *Debugger) REPL(in io.Reader, out io.Writer) error {
+	scanner := bufio.NewScanner(in)
+	for {
+		fmt.Fprint(out, "(debug) ")
+		if !scanner.Scan() {
+			return scanner.Err()
+		}
+
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 {
+			continue
+		}
+
+		switch fields[0] {
+		case "break", "b":
+			addr, err := parseAddr(fields, 1)
+			if err != nil {
+				fmt.Fprintln(out, err)
+				continue
+			}
+			d.SetBreakpoint(addr)
+		case "clear":
+			addr, err := parseAddr(fields, 1)
+			if err != nil {
+				fmt.Fprintln(out, err)
+				continue
+			}
+			d.ClearBreakpoint(addr)
+		case "step", "s":
+			if halted := report(out, d.Step()); halted {
+				return nil
+			}
+		case "next", "n":
+			if halted := report(out, d.StepOver()); halted {
+				return nil
+			}
+		case "continue", "c":
+			if halted := report(out, d.Continue()); halted {
+				return nil
+			}
+		case "bt", "backtrace":
+			for _, addr := range d.Backtrace() {
+				fmt.Fprintf(out, "  %05d\n", addr)
+			}
+		case "reg":
+			if len(fields) >= 3 {
+				idx, err := strconv.ParseUint(fields[1], 0, 16)
+				if err != nil {
+					fmt.Fprintln(out, err)
+					continue
+				}
+				val, err := strconv.ParseUint(fields[2], 0, 16)
+				if err != nil {
+					fmt.Fprintln(out, err)
+					continue
+				}
+				if err := d.WriteRegister(uint16(idx), uint16(val)); err != nil {
+					fmt.Fprintln(out, err)
+				}
+				continue
+			}
+			for i, v := range d.Machine.Registers() {
+				fmt.Fprintf(out, "  r%d = %05d\n", i, v)
+			}
+		case "mem":
+			if len(fields) < 2 {
+				fmt.Fprintln(out, "usage: mem <addr> [value]")
+				continue
+			}
+			addr, err := parseAddr(fields, 1)
+			if err != nil {
+				fmt.Fprintln(out, err)
+				continue
+			}
+			if len(fields) >= 3 {
+				val, err := strconv.ParseUint(fields[2], 0, 16)
+				if err != nil {
+					fmt.Fprintln(out, err)
+					continue
+				}
+				d.WriteMemory(addr, uint16(val))
+				continue
+			}
+			fmt.Fprintf(out, "  [%05d] = %05d\n", addr, d.Machine.ReadMemory(addr))
+		case "save":
+			if len(fields) < 2 {
+				fmt.Fprintln(out, "usage: save <file>")
+				continue
+			}
+			if err := d.Save(fields[1]); err != nil {
+				fmt.Fprintln(out, err)
+			}
+		case "load":
+			if len(fields) < 2 {
+				fmt.Fprintln(out, "usage: load <file>")
+				continue
+			}
+			if err := d.Load(fields[1]); err != nil {
+				fmt.Fprintln(out, err)
+			}
+		case "quit", "q":
+			return nil
+		default:
+			fmt.Fprintf(out, "unknown command %q\n", fields[0])
+		}
+	}
+}
+
+func parseAddr(fields []string, i int) (uint16, error) {
+	if i >= len(fields) {
+		return 0, fmt.Errorf("missing address")
+	}
+	addr, err := strconv.ParseUint(fields[i], 0, 16)
+	if err != nil {
+		return 0, err
+	}
+	return uint16(addr), nil
+}
+
+// report prints the outcome of a stepping command and reports whether the
+// machine has halted (or otherwise stopped for good), in which case the
+// REPL should exit.
+func report(out io.Writer, err error) bool {
+	switch err {
+	case nil:
+		return false
+	case vm.ErrorHalt:
+		fmt.Fprintln(out, "machine halted")
+		return true
+	default:
+		fmt.Fprintln(out, err)
+		return true
+	}
+}
@@ -0,0 +1,54 @@
+package disasm
+
+import (
+	"fmt"
+	"strings"
+)
+
+// CFG renders the control-flow graph of instrs as a DOT digraph, suitable
+// for visualization with Graphviz. Calls are drawn as dashed edges to
+// distinguish them from branches and fallthrough.
+func CFG(instrs []Instruction) string {
+	var b strings.Builder
+	b.WriteString("digraph cfg {\n")
+	b.WriteString("  node [shape=box, fontname=monospace];\n")
+
+	for i, instr := range instrs {
+		fmt.Fprintf(&b, "  n%d [label=%q];\n", instr.Address, instr.String())
+
+		switch instr.Mnemonic {
+		case "jmp":
+			edgeTo(&b, instr.Address, instr.Operands[0], "")
+		case "jt", "jf":
+			edgeTo(&b, instr.Address, instr.Operands[1], "")
+			fallthroughEdge(&b, instrs, i)
+		case "call":
+			edgeTo(&b, instr.Address, instr.Operands[0], "style=dashed")
+			fallthroughEdge(&b, instrs, i)
+		case "halt", "ret":
+			// no successors
+		default:
+			fallthroughEdge(&b, instrs, i)
+		}
+	}
+
+	b.WriteString("}\n")
+	return b.String()
+}
+
+func edgeTo(b *strings.Builder, from uint16, target Operand, attrs string) {
+	if target.Kind != OperandLiteral {
+		return
+	}
+	if attrs != "" {
+		fmt.Fprintf(b, "  n%d -> n%d [%s];\n", from, target.Value, attrs)
+		return
+	}
+	fmt.Fprintf(b, "  n%d -> n%d;\n", from, target.Value)
+}
+
+func fallthroughEdge(b *strings.Builder, instrs []Instruction, i int) {
+	if i+1 < len(instrs) {
+		fmt.Fprintf(b, "  n%d -> n%d;\n", instrs[i].Address, instrs[i+1].Address)
+	}
+}
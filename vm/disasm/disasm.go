@@ -0,0 +1,186 @@
+// Package disasm provides a disassembler and simple static analysis for
+// images loaded into a vm.Machine.
+package disasm
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/aneurysm9/challenger/vm"
+)
+
+// OperandKind distinguishes a register operand from a literal one.
+type OperandKind int
+
+const (
+	// OperandLiteral is a literal value, or a memory address for
+	// destination operands.
+	OperandLiteral OperandKind = iota
+	// OperandRegister is a register number.
+	OperandRegister
+)
+
+// Operand is a single decoded instruction operand.
+type Operand struct {
+	Kind  OperandKind
+	Value uint16
+}
+
+func (o Operand) String() string {
+	if o.Kind == OperandRegister {
+		return fmt.Sprintf("r%d", o.Value)
+	}
+	return fmt.Sprintf("%d", o.Value)
+}
+
+// Instruction is a single decoded instruction or, for unreached regions,
+// a single word of data.
+type Instruction struct {
+	Address  uint16
+	Opcode   uint16
+	Mnemonic string
+	Operands []Operand
+	Size     uint16
+}
+
+func (i Instruction) String() string {
+	operands := make([]string, len(i.Operands))
+	for j, op := range i.Operands {
+		operands[j] = op.String()
+	}
+	if len(operands) == 0 {
+		return fmt.Sprintf("%05d: %s", i.Address, i.Mnemonic)
+	}
+	return fmt.Sprintf("%05d: %s %s", i.Address, i.Mnemonic, strings.Join(operands, ", "))
+}
+
+type opInfo struct {
+	mnemonic string
+	size     uint16
+}
+
+var opcodes = map[uint16]opInfo{
+	0:  {"halt", 1},
+	1:  {"set", 3},
+	2:  {"push", 2},
+	3:  {"pop", 2},
+	4:  {"eq", 4},
+	5:  {"gt", 4},
+	6:  {"jmp", 2},
+	7:  {"jt", 3},
+	8:  {"jf", 3},
+	9:  {"add", 4},
+	10: {"mult", 4},
+	11: {"mod", 4},
+	12: {"and", 4},
+	13: {"or", 4},
+	14: {"not", 3},
+	15: {"rmem", 3},
+	16: {"wmem", 3},
+	17: {"call", 2},
+	18: {"ret", 1},
+	19: {"out", 2},
+	20: {"in", 2},
+	21: {"noop", 1},
+}
+
+// Mnemonic returns the mnemonic for opcode op, or "data" if op is not a
+// known opcode.
+func Mnemonic(op uint16) string {
+	if info, ok := opcodes[op]; ok {
+		return info.mnemonic
+	}
+	return "data"
+}
+
+func operand(v uint16) Operand {
+	if vm.IsRegister(v) {
+		return Operand{Kind: OperandRegister, Value: vm.RegisterIndex(v)}
+	}
+	return Operand{Kind: OperandLiteral, Value: v}
+}
+
+// DecodeOne decodes the instruction or data word at addr, without regard to
+// whether it is reachable code. It is also used by the trace package to
+// label instructions in a captured trace.
+func DecodeOne(m *vm.Machine, addr uint16) Instruction {
+	op := m.ReadMemory(addr)
+	info, ok := opcodes[op]
+	if !ok {
+		return Instruction{Address: addr, Mnemonic: "data", Operands: []Operand{{Kind: OperandLiteral, Value: op}}, Size: 1}
+	}
+
+	instr := Instruction{Address: addr, Opcode: op, Mnemonic: info.mnemonic, Size: info.size}
+	for i := uint16(1); i < info.size; i++ {
+		instr.Operands = append(instr.Operands, operand(m.ReadMemory(addr+i)))
+	}
+	return instr
+}
+
+// Disassemble decodes the instructions between start and end (exclusive),
+// following reachability from address 0 and from call targets to tell
+// executable code from embedded data. Unreached words are returned as
+// single-word "data" pseudo-instructions.
+func Disassemble(m *vm.Machine, start, end uint16) []Instruction {
+	reached := reachable(m, end)
+
+	var instrs []Instruction
+	for addr := start; addr < end; {
+		if !reached[addr] {
+			instrs = append(instrs, Instruction{Address: addr, Mnemonic: "data", Operands: []Operand{{Kind: OperandLiteral, Value: m.ReadMemory(addr)}}, Size: 1})
+			addr++
+			continue
+		}
+
+		instr := DecodeOne(m, addr)
+		instrs = append(instrs, instr)
+		addr += instr.Size
+	}
+	return instrs
+}
+
+// reachable performs a simple recursive-descent walk from address 0,
+// following jmp/jt/jf/call targets, to find which addresses below end hold
+// executable code.
+func reachable(m *vm.Machine, end uint16) map[uint16]bool {
+	seen := make(map[uint16]bool)
+	queue := []uint16{0}
+
+	for len(queue) > 0 {
+		addr := queue[0]
+		queue = queue[1:]
+
+		for addr < end && !seen[addr] {
+			op := m.ReadMemory(addr)
+			info, ok := opcodes[op]
+			if !ok {
+				break
+			}
+			seen[addr] = true
+
+			switch op {
+			case 6: // jmp
+				if target := m.ReadMemory(addr + 1); vm.IsMemory(target) {
+					queue = append(queue, target)
+				}
+				addr = end
+			case 7, 8: // jt, jf
+				if target := m.ReadMemory(addr + 2); vm.IsMemory(target) {
+					queue = append(queue, target)
+				}
+				addr += info.size
+			case 17: // call
+				if target := m.ReadMemory(addr + 1); vm.IsMemory(target) {
+					queue = append(queue, target)
+				}
+				addr += info.size
+			case 0, 18: // halt, ret
+				addr = end
+			default:
+				addr += info.size
+			}
+		}
+	}
+
+	return seen
+}
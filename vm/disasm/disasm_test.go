@@ -0,0 +1,78 @@
+package disasm
+
+import (
+	"testing"
+
+	"github.com/aneurysm9/challenger/vm"
+)
+
+// program is:
+//
+//	0: jmp 3          ; 6, 3
+//	2: 1234           ; embedded data, never reached
+//	3: noop           ; 21
+//	4: out r0         ; 19, 32768
+//	6: halt           ; 0
+func program() *vm.Machine {
+	m := vm.NewMachine()
+	words := []uint16{6, 3, 1234, 21, 19, 1 << 15, 0}
+	for addr, w := range words {
+		m.WriteMemory(uint16(addr), w)
+	}
+	return m
+}
+
+func TestDisassembleSkipsUnreachedData(t *testing.T) {
+	m := program()
+	instrs := Disassemble(m, 0, 7)
+
+	want := []struct {
+		addr     uint16
+		mnemonic string
+	}{
+		{0, "jmp"},
+		{2, "data"},
+		{3, "noop"},
+		{4, "out"},
+		{6, "halt"},
+	}
+
+	if len(instrs) != len(want) {
+		t.Fatalf("got %d instructions, want %d: %+v", len(instrs), len(want), instrs)
+	}
+	for i, w := range want {
+		if instrs[i].Address != w.addr || instrs[i].Mnemonic != w.mnemonic {
+			t.Errorf("instrs[%d] = %+v, want addr=%d mnemonic=%s", i, instrs[i], w.addr, w.mnemonic)
+		}
+	}
+}
+
+func TestDisassembleClassifiesOperands(t *testing.T) {
+	m := program()
+	instrs := Disassemble(m, 0, 7)
+
+	var out *Instruction
+	for i := range instrs {
+		if instrs[i].Mnemonic == "out" {
+			out = &instrs[i]
+		}
+	}
+	if out == nil {
+		t.Fatal("out instruction not found")
+	}
+	if len(out.Operands) != 1 || out.Operands[0].Kind != OperandRegister || out.Operands[0].Value != 0 {
+		t.Errorf("out operand = %+v, want register 0", out.Operands)
+	}
+}
+
+func TestReachableStopsAtUnconditionalJump(t *testing.T) {
+	m := program()
+	seen := reachable(m, 7)
+
+	if seen[2] {
+		t.Error("address 2 (embedded data after an unconditional jmp) marked reachable")
+	}
+	if !seen[0] || !seen[3] || !seen[4] || !seen[6] {
+		t.Errorf("reachable = %+v, want 0, 3, 4, 6 reachable", seen)
+	}
+}
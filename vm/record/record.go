@@ -0,0 +1,82 @@
+// Package record captures and replays the input a vm.Machine consumes, so
+// that a puzzle solution can be reproduced deterministically.
+package record
+
+import (
+	"encoding/gob"
+	"io"
+
+	"github.com/aneurysm9/challenger/vm"
+)
+
+// Event is a single byte of input consumed by the machine, along with the
+// instruction pointer at which it was read.
+type Event struct {
+	IP    uint16
+	Value byte
+}
+
+// Recorder wraps an io.Reader, typically os.Stdin, capturing every byte
+// read along with the machine's ip at the time it was consumed.
+type Recorder struct {
+	r      io.Reader
+	m      *vm.Machine
+	events []Event
+}
+
+// NewRecorder returns a Recorder that reads from r, tagging each byte with
+// m's instruction pointer at the time it is consumed.
+func NewRecorder(r io.Reader, m *vm.Machine) *Recorder {
+	return &Recorder{r: r, m: m}
+}
+
+// Read implements io.Reader, recording every byte it returns.
+func (rec *Recorder) Read(p []byte) (int, error) {
+	n, err := rec.r.Read(p)
+	for i := 0; i < n; i++ {
+		rec.events = append(rec.events, Event{IP: rec.m.IP(), Value: p[i]})
+	}
+	return n, err
+}
+
+// Events returns the transcript captured so far.
+func (rec *Recorder) Events() []Event {
+	return rec.events
+}
+
+// Save writes the captured transcript to w.
+func (rec *Recorder) Save(w io.Writer) error {
+	return gob.NewEncoder(w).Encode(rec.events)
+}
+
+// Replayer is an io.Reader that feeds a previously captured transcript back
+// into a fresh machine, reproducing the same input deterministically.
+type Replayer struct {
+	events []Event
+	pos    int
+}
+
+// Load reads a transcript previously written by Recorder.Save.
+func Load(r io.Reader) (*Replayer, error) {
+	var events []Event
+	if err := gob.NewDecoder(r).Decode(&events); err != nil {
+		return nil, err
+	}
+	return &Replayer{events: events}, nil
+}
+
+// Read implements io.Reader, returning io.EOF once the transcript is
+// exhausted.
+func (rep *Replayer) Read(p []byte) (int, error) {
+	if rep.pos >= len(rep.events) {
+		return 0, io.EOF
+	}
+
+	n := 0
+	for n < len(p) && rep.pos < len(rep.events) {
+		p[n] = rep.events[rep.pos].Value
+		n++
+		rep.pos++
+	}
+	return n, nil
+}
@@ -0,0 +1,56 @@
+package record
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/aneurysm9/challenger/vm"
+)
+
+func TestRecorderReplayRoundTrip(t *testing.T) {
+	m := vm.NewMachine()
+	rec := NewRecorder(strings.NewReader("hi\r"), m)
+
+	buf := make([]byte, 16)
+	n, err := rec.Read(buf)
+	if err != nil && err != io.EOF {
+		t.Fatalf("Read: %v", err)
+	}
+	if got, want := string(buf[:n]), "hi\r"; got != want {
+		t.Fatalf("Read = %q, want %q", got, want)
+	}
+
+	want := rec.Events()
+	if len(want) != 3 {
+		t.Fatalf("Events = %v, want 3 events", want)
+	}
+
+	var saved bytes.Buffer
+	if err := rec.Save(&saved); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+
+	rep, err := Load(&saved)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+
+	got, err := io.ReadAll(rep)
+	if err != nil {
+		t.Fatalf("ReadAll: %v", err)
+	}
+	if string(got) != "hi\r" {
+		t.Errorf("replayed input = %q, want %q", got, "hi\r")
+	}
+}
+
+func TestReplayerEOFWhenExhausted(t *testing.T) {
+	rep := &Replayer{}
+
+	buf := make([]byte, 4)
+	if n, err := rep.Read(buf); n != 0 || err != io.EOF {
+		t.Errorf("Read on empty transcript = (%d, %v), want (0, io.EOF)", n, err)
+	}
+}
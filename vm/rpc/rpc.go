@@ -0,0 +1,230 @@
+// Package rpc exposes a vm.Machine under debugger control over JSON-RPC,
+// so that external tooling can drive it without embedding Go. The wire
+// protocol is the JSON-RPC 1.0 one that net/rpc/jsonrpc implements:
+// responses carry no "jsonrpc" member and errors are plain strings, so it
+// is not JSON-RPC 2.0.
+package rpc
+
+import (
+	"bytes"
+	"net"
+	"net/rpc"
+	"net/rpc/jsonrpc"
+	"sync"
+
+	"github.com/aneurysm9/challenger/vm/debug"
+	"github.com/aneurysm9/challenger/vm/disasm"
+)
+
+// Challenger is the JSON-RPC service registered by Serve. Its methods
+// follow the net/rpc convention of func(args *Args, reply *Reply) error.
+//
+// net/rpc dispatches each request on its own goroutine, including requests
+// pipelined on the same connection and requests from different
+// connections, so every method takes mu before touching the shared
+// Debugger/Machine.
+type Challenger struct {
+	mu sync.Mutex
+	d  *debug.Debugger
+}
+
+// StepArgs is unused but present for symmetry with net/rpc's calling
+// convention.
+type StepArgs struct{}
+
+// StepReply reports the instruction pointer after a step.
+type StepReply struct {
+	IP uint16
+}
+
+// Step executes a single instruction.
+func (c *Challenger) Step(args *StepArgs, reply *StepReply) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err := c.d.Step(); err != nil {
+		return err
+	}
+	reply.IP = c.d.Machine.IP()
+	return nil
+}
+
+// ContinueArgs is unused but present for symmetry with net/rpc's calling
+// convention.
+type ContinueArgs struct{}
+
+// ContinueReply reports the instruction pointer where execution stopped.
+type ContinueReply struct {
+	IP uint16
+}
+
+// Continue runs until halt, error, or breakpoint.
+func (c *Challenger) Continue(args *ContinueArgs, reply *ContinueReply) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err := c.d.Continue(); err != nil {
+		return err
+	}
+	reply.IP = c.d.Machine.IP()
+	return nil
+}
+
+// SetBreakpointArgs names the address to break on.
+type SetBreakpointArgs struct {
+	Address uint16
+}
+
+// SetBreakpointReply is empty; the call either succeeds or returns an error.
+type SetBreakpointReply struct{}
+
+// SetBreakpoint sets a breakpoint at args.Address.
+func (c *Challenger) SetBreakpoint(args *SetBreakpointArgs, reply *SetBreakpointReply) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.d.SetBreakpoint(args.Address)
+	return nil
+}
+
+// ReadMemoryArgs names the address range to read.
+type ReadMemoryArgs struct {
+	Address uint16
+	Length  uint16
+}
+
+// ReadMemoryReply holds the words read.
+type ReadMemoryReply struct {
+	Values []uint16
+}
+
+// ReadMemory reads args.Length words starting at args.Address.
+func (c *Challenger) ReadMemory(args *ReadMemoryArgs, reply *ReadMemoryReply) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	reply.Values = c.d.ReadMemory(args.Address, args.Length)
+	return nil
+}
+
+// WriteMemoryArgs names a single word to patch.
+type WriteMemoryArgs struct {
+	Address uint16
+	Value   uint16
+}
+
+// WriteMemoryReply is empty; the call either succeeds or returns an error.
+type WriteMemoryReply struct{}
+
+// WriteMemory patches a single word of memory.
+func (c *Challenger) WriteMemory(args *WriteMemoryArgs, reply *WriteMemoryReply) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.d.WriteMemory(args.Address, args.Value)
+	return nil
+}
+
+// GetRegistersArgs is unused but present for symmetry with net/rpc's
+// calling convention.
+type GetRegistersArgs struct{}
+
+// GetRegistersReply holds the register file.
+type GetRegistersReply struct {
+	Registers [8]uint16
+}
+
+// GetRegisters returns the machine's registers.
+func (c *Challenger) GetRegisters(args *GetRegistersArgs, reply *GetRegistersReply) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	reply.Registers = c.d.Machine.Registers()
+	return nil
+}
+
+// SnapshotArgs is unused but present for symmetry with net/rpc's calling
+// convention.
+type SnapshotArgs struct{}
+
+// SnapshotReply holds the serialized machine state.
+type SnapshotReply struct {
+	Data []byte
+}
+
+// Snapshot serializes the machine's current state.
+func (c *Challenger) Snapshot(args *SnapshotArgs, reply *SnapshotReply) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	data, err := c.d.Machine.Snapshot()
+	if err != nil {
+		return err
+	}
+	reply.Data = data
+	return nil
+}
+
+// RestoreArgs holds a snapshot previously produced by Snapshot.
+type RestoreArgs struct {
+	Data []byte
+}
+
+// RestoreReply is empty; the call either succeeds or returns an error.
+type RestoreReply struct{}
+
+// Restore replaces the machine's state with args.Data.
+func (c *Challenger) Restore(args *RestoreArgs, reply *RestoreReply) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.d.Machine.Restore(bytes.NewReader(args.Data))
+}
+
+// DisassembleArgs names the address range to disassemble.
+type DisassembleArgs struct {
+	Start uint16
+	End   uint16
+}
+
+// DisassembleReply holds one rendered listing line per instruction.
+type DisassembleReply struct {
+	Listing []string
+}
+
+// Disassemble disassembles the given address range.
+func (c *Challenger) Disassemble(args *DisassembleArgs, reply *DisassembleReply) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	instrs := disasm.Disassemble(c.d.Machine, args.Start, args.End)
+	reply.Listing = make([]string, len(instrs))
+	for i, instr := range instrs {
+		reply.Listing[i] = instr.String()
+	}
+	return nil
+}
+
+// Serve registers a Challenger service wrapping d and accepts JSON-RPC 1.0
+// connections on network/addr (e.g. "tcp", ":7000" or "unix",
+// "/tmp/challenger.sock") until the listener errors.
+func Serve(d *debug.Debugger, network, addr string) error {
+	server := rpc.NewServer()
+	if err := server.RegisterName("Challenger", &Challenger{d: d}); err != nil {
+		return err
+	}
+
+	l, err := net.Listen(network, addr)
+	if err != nil {
+		return err
+	}
+	defer l.Close()
+
+	for {
+		conn, err := l.Accept()
+		if err != nil {
+			return err
+		}
+		go server.ServeCodec(jsonrpc.NewServerCodec(conn))
+	}
+}
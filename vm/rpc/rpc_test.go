@@ -0,0 +1,77 @@
+package rpc
+
+import (
+	"net"
+	"net/rpc/jsonrpc"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aneurysm9/challenger/vm"
+	"github.com/aneurysm9/challenger/vm/debug"
+)
+
+// TestServeConcurrentClients drives Serve with several clients issuing
+// requests concurrently against the same Debugger. Run with -race: it is a
+// regression test for the data race Challenger.mu fixes, since net/rpc
+// dispatches each request (including pipelined ones) on its own goroutine.
+func TestServeConcurrentClients(t *testing.T) {
+	d := debug.NewDebugger(vm.NewMachine())
+
+	sock := filepath.Join(t.TempDir(), "challenger.sock")
+	go Serve(d, "unix", sock)
+
+	// Serve's listener isn't guaranteed to be up the instant the goroutine is
+	// scheduled; retry the first dial until it comes up.
+	var probe net.Conn
+	var err error
+	for i := 0; i < 100; i++ {
+		probe, err = net.Dial("unix", sock)
+		if err == nil {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+	probe.Close()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(addr uint16) {
+			defer wg.Done()
+
+			conn, err := net.Dial("unix", sock)
+			if err != nil {
+				t.Errorf("dial: %v", err)
+				return
+			}
+			defer conn.Close()
+			client := jsonrpc.NewClient(conn)
+			defer client.Close()
+
+			for j := 0; j < 20; j++ {
+				var wreply WriteMemoryReply
+				args := &WriteMemoryArgs{Address: addr, Value: uint16(j)}
+				if err := client.Call("Challenger.WriteMemory", args, &wreply); err != nil {
+					t.Errorf("WriteMemory: %v", err)
+					return
+				}
+				var rreply ReadMemoryReply
+				if err := client.Call("Challenger.ReadMemory", &ReadMemoryArgs{Address: addr, Length: 1}, &rreply); err != nil {
+					t.Errorf("ReadMemory: %v", err)
+					return
+				}
+				var greply GetRegistersReply
+				if err := client.Call("Challenger.GetRegisters", &GetRegistersArgs{}, &greply); err != nil {
+					t.Errorf("GetRegisters: %v", err)
+					return
+				}
+			}
+		}(uint16(i))
+	}
+	wg.Wait()
+}
@@ -0,0 +1,108 @@
+package vm
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/gob"
+	"fmt"
+	"io"
+
+	stck "github.com/golang-collections/collections/stack"
+)
+
+// snapshotMagic identifies a challenger save file.
+var snapshotMagic = []byte("CHLS")
+
+// snapshotVersion is the version of the snapshot payload format. Bump it
+// whenever snapshotState changes shape.
+const snapshotVersion byte = 1
+
+// snapshotState is the gob-encoded, gzip-compressed payload of a snapshot.
+type snapshotState struct {
+	Memory    []uint16
+	Registers [8]uint16
+	Stack     []uint16
+	CallStack []uint16
+	IP        uint16
+}
+
+// Snapshot serializes the machine's full state - memory, registers, stack,
+// and ip - into a versioned binary format, so it can be restored later with
+// Restore.
+func (m *Machine) Snapshot() ([]byte, error) {
+	buf := &bytes.Buffer{}
+	buf.Write(snapshotMagic)
+	buf.WriteByte(snapshotVersion)
+
+	gz := gzip.NewWriter(buf)
+	state := snapshotState{
+		Memory:    m.memory,
+		Registers: m.registers,
+		Stack:     m.drainStack(),
+		CallStack: m.callStack,
+		IP:        m.ip,
+	}
+	if err := gob.NewEncoder(gz).Encode(&state); err != nil {
+		return nil, err
+	}
+	if err := gz.Close(); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// Restore replaces the machine's state with a snapshot previously produced
+// by Snapshot.
+func (m *Machine) Restore(r io.Reader) error {
+	header := make([]byte, len(snapshotMagic)+1)
+	if _, err := io.ReadFull(r, header); err != nil {
+		return fmt.Errorf("reading snapshot header: %w", err)
+	}
+	if !bytes.Equal(header[:len(snapshotMagic)], snapshotMagic) {
+		return fmt.Errorf("not a challenger snapshot file")
+	}
+	if version := header[len(snapshotMagic)]; version != snapshotVersion {
+		return fmt.Errorf("unsupported snapshot version %d", version)
+	}
+
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return fmt.Errorf("reading snapshot payload: %w", err)
+	}
+	defer gz.Close()
+
+	var state snapshotState
+	if err := gob.NewDecoder(gz).Decode(&state); err != nil {
+		return fmt.Errorf("decoding snapshot payload: %w", err)
+	}
+
+	m.memory = state.Memory
+	m.registers = state.Registers
+	m.callStack = state.CallStack
+	m.ip = state.IP
+	m.fillStack(state.Stack)
+
+	return nil
+}
+
+// drainStack empties the machine's data stack into a slice, top of stack
+// first, and restores it to its original order. The stck.Stack type does
+// not expose its contents directly, so this is the only way to read them.
+func (m *Machine) drainStack() []uint16 {
+	vals := make([]uint16, 0, m.stack.Len())
+	for m.stack.Len() > 0 {
+		vals = append(vals, m.stack.Pop().(uint16))
+	}
+	m.fillStack(vals)
+	return vals
+}
+
+// fillStack replaces the machine's data stack with vals, given top of stack
+// first (the order drainStack produces).
+func (m *Machine) fillStack(vals []uint16) {
+	m.stack = stck.New()
+	for i := len(vals) - 1; i >= 0; i-- {
+		m.stack.Push(vals[i])
+	}
+}
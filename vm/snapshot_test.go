@@ -0,0 +1,63 @@
+package vm
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestSnapshotRestoreRoundTrip(t *testing.T) {
+	m := NewMachine()
+	m.WriteMemory(0, 9) // add r0, 1, 2
+	m.WriteMemory(1, 1<<15)
+	m.WriteMemory(2, 1)
+	m.WriteMemory(3, 2)
+	m.WriteMemory(4, 19) // out 'a'
+	m.WriteMemory(5, 97)
+	m.WriteMemory(6, 0) // halt
+
+	m.stack.Push(uint16(42))
+	m.callStack = []uint16{5}
+
+	data, err := m.Snapshot()
+	if err != nil {
+		t.Fatalf("Snapshot: %v", err)
+	}
+
+	restored := NewMachine()
+	if err := restored.Restore(bytes.NewReader(data)); err != nil {
+		t.Fatalf("Restore: %v", err)
+	}
+
+	if restored.IP() != m.IP() {
+		t.Errorf("IP = %d, want %d", restored.IP(), m.IP())
+	}
+	if restored.Registers() != m.Registers() {
+		t.Errorf("Registers = %v, want %v", restored.Registers(), m.Registers())
+	}
+	for addr := uint16(0); addr < 7; addr++ {
+		if got, want := restored.ReadMemory(addr), m.ReadMemory(addr); got != want {
+			t.Errorf("memory[%d] = %d, want %d", addr, got, want)
+		}
+	}
+	if got, want := restored.StackDepth(), m.StackDepth(); got != want {
+		t.Errorf("StackDepth = %d, want %d", got, want)
+	}
+	if callStack := restored.CallStack(); len(callStack) != 1 || callStack[0] != 5 {
+		t.Errorf("CallStack = %v, want [5]", callStack)
+	}
+
+	var bottom uint16
+	for restored.StackDepth() > 0 {
+		bottom = restored.stack.Pop().(uint16)
+	}
+	if bottom != 42 {
+		t.Errorf("bottom of restored stack = %d, want 42", bottom)
+	}
+}
+
+func TestRestoreRejectsBadMagic(t *testing.T) {
+	m := NewMachine()
+	if err := m.Restore(bytes.NewReader([]byte("not a snapshot"))); err == nil {
+		t.Error("Restore with bad magic: got nil error, want one")
+	}
+}
@@ -0,0 +1,72 @@
+package trace
+
+import (
+	"bufio"
+	"io"
+)
+
+// CallEdge is a single observed call from one address to another.
+type CallEdge struct {
+	From uint16
+	To   uint16
+}
+
+// Stats summarizes a trace: opcode frequency, per-address execution counts
+// (a proxy for hot basic blocks), call-graph edges, and per-function
+// instruction counts derived from matching call/ret pairs.
+type Stats struct {
+	OpcodeCounts   map[uint16]int
+	AddressCounts  map[uint16]int
+	CallEdges      map[CallEdge]int
+	FunctionInstrs map[uint16]int
+}
+
+const (
+	callOpcode = 17
+	retOpcode  = 18
+)
+
+// Analyze reads a trace previously written by a Tracer and computes summary
+// statistics over it.
+func Analyze(r io.Reader) (*Stats, error) {
+	stats := &Stats{
+		OpcodeCounts:   make(map[uint16]int),
+		AddressCounts:  make(map[uint16]int),
+		CallEdges:      make(map[CallEdge]int),
+		FunctionInstrs: make(map[uint16]int),
+	}
+
+	br := bufio.NewReader(r)
+	var callStack []uint16
+
+	for {
+		evt, err := DecodeEvent(br)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		stats.OpcodeCounts[evt.Opcode]++
+		stats.AddressCounts[evt.IP]++
+		if len(callStack) > 0 {
+			stats.FunctionInstrs[callStack[len(callStack)-1]]++
+		}
+
+		switch evt.Opcode {
+		case callOpcode:
+			if len(evt.Operands) > 0 {
+				target := evt.Operands[0]
+				stats.CallEdges[CallEdge{From: evt.IP, To: target}]++
+				callStack = append(callStack, target)
+			}
+		case retOpcode:
+			if len(callStack) > 0 {
+				callStack = callStack[:len(callStack)-1]
+			}
+		}
+	}
+
+	return stats, nil
+}
@@ -0,0 +1,105 @@
+package trace
+
+import (
+	"bufio"
+	"encoding/binary"
+	"io"
+)
+
+// Encode writes e to w in a compact binary format: fixed-width fields with
+// byte-length-prefixed variable arrays, all integers little-endian.
+func (e Event) Encode(w io.Writer) error {
+	buf := make([]byte, 0, 8+4*len(e.Operands)+5*len(e.Writes)+2)
+
+	buf = appendU16(buf, e.IP)
+	buf = appendU16(buf, e.Opcode)
+
+	buf = append(buf, byte(len(e.Operands)))
+	for _, op := range e.Operands {
+		buf = appendU16(buf, op)
+	}
+
+	buf = append(buf, byte(len(e.Writes)))
+	for _, wr := range e.Writes {
+		buf = append(buf, byte(wr.Kind))
+		buf = appendU16(buf, wr.Loc)
+		buf = appendU16(buf, wr.Value)
+	}
+
+	buf = appendU16(buf, uint16(e.StackDepth))
+
+	_, err := w.Write(buf)
+	return err
+}
+
+// DecodeEvent reads a single Event from r, as written by Event.Encode. It
+// returns io.EOF once the stream is exhausted between events.
+func DecodeEvent(r *bufio.Reader) (Event, error) {
+	var e Event
+
+	ip, err := readU16(r)
+	if err != nil {
+		return e, err
+	}
+	e.IP = ip
+
+	opcode, err := readU16(r)
+	if err != nil {
+		return e, err
+	}
+	e.Opcode = opcode
+
+	nOperands, err := r.ReadByte()
+	if err != nil {
+		return e, err
+	}
+	for i := byte(0); i < nOperands; i++ {
+		v, err := readU16(r)
+		if err != nil {
+			return e, err
+		}
+		e.Operands = append(e.Operands, v)
+	}
+
+	nWrites, err := r.ReadByte()
+	if err != nil {
+		return e, err
+	}
+	for i := byte(0); i < nWrites; i++ {
+		kind, err := r.ReadByte()
+		if err != nil {
+			return e, err
+		}
+		loc, err := readU16(r)
+		if err != nil {
+			return e, err
+		}
+		val, err := readU16(r)
+		if err != nil {
+			return e, err
+		}
+		e.Writes = append(e.Writes, Write{Kind: WriteKind(kind), Loc: loc, Value: val})
+	}
+
+	depth, err := readU16(r)
+	if err != nil {
+		return e, err
+	}
+	e.StackDepth = int(depth)
+
+	return e, nil
+}
+
+func appendU16(buf []byte, v uint16) []byte {
+	var b [2]byte
+	binary.LittleEndian.PutUint16(b[:], v)
+	return append(buf, b[:]...)
+}
+
+func readU16(r io.Reader) (uint16, error) {
+	var b [2]byte
+	if _, err := io.ReadFull(r, b[:]); err != nil {
+		return 0, err
+	}
+	return binary.LittleEndian.Uint16(b[:]), nil
+}
@@ -0,0 +1,154 @@
+// Package trace provides an opt-in tracer for a vm.Machine, emitting a
+// structured event per executed instruction for post-hoc analysis.
+package trace
+
+import (
+	"io"
+
+	"github.com/aneurysm9/challenger/vm"
+	"github.com/aneurysm9/challenger/vm/disasm"
+)
+
+// WriteKind distinguishes a register write from a memory write.
+type WriteKind byte
+
+const (
+	// WriteRegister is a write to a register.
+	WriteRegister WriteKind = iota
+	// WriteMemory is a write to a memory address.
+	WriteMemory
+)
+
+// Write records a single register or memory write caused by an instruction.
+type Write struct {
+	Kind  WriteKind
+	Loc   uint16
+	Value uint16
+}
+
+// Event is a single executed instruction, captured for later analysis. For
+// a call instruction, Operands holds the resolved jump target (the address
+// call() actually assigned to ip), not the raw register-or-literal operand
+// a static disassembly would show - a register-indirect call's target can
+// only be known at the point it executes.
+type Event struct {
+	IP         uint16
+	Opcode     uint16
+	Operands   []uint16
+	Writes     []Write
+	StackDepth int
+}
+
+// opcodes with a destination operand at ip+1 whose raw value (register or
+// memory address) is resolved the same way setVal resolves it. wmem is
+// handled separately, since its destination is itself resolved through
+// getVal before being used as a memory address.
+var destOperand = map[uint16]bool{
+	1: true, 3: true, 4: true, 5: true, 9: true, 10: true,
+	11: true, 12: true, 13: true, 14: true, 15: true, 20: true,
+}
+
+const wmemOpcode = 16
+
+// Tracer captures a structured Event for every instruction a Machine
+// executes, by chaining onto its PreStep and PostStep hooks.
+type Tracer struct {
+	m *vm.Machine
+	w io.Writer
+
+	prevRegs     [8]uint16
+	prevIP       uint16
+	prevOpcode   uint16
+	prevOperands []uint16
+	prevDest     uint16
+	prevHasDest  bool
+}
+
+// Attach installs a Tracer on m, writing one Event to w per instruction
+// executed from this point on. Any PreStep/PostStep hooks already set on m
+// are preserved and run alongside the tracer's own.
+func Attach(m *vm.Machine, w io.Writer) *Tracer {
+	t := &Tracer{m: m, w: w}
+
+	prevPre := m.PreStep
+	m.PreStep = func(mm *vm.Machine) error {
+		if prevPre != nil {
+			if err := prevPre(mm); err != nil {
+				return err
+			}
+		}
+		t.before(mm)
+		return nil
+	}
+
+	prevPost := m.PostStep
+	m.PostStep = func(mm *vm.Machine) {
+		if prevPost != nil {
+			prevPost(mm)
+		}
+		t.after(mm)
+	}
+
+	return t
+}
+
+func (t *Tracer) before(m *vm.Machine) {
+	t.prevRegs = m.Registers()
+	t.prevIP = m.IP()
+
+	instr := disasm.DecodeOne(m, t.prevIP)
+	t.prevOpcode = instr.Opcode
+	t.prevOperands = make([]uint16, len(instr.Operands))
+	for i, op := range instr.Operands {
+		t.prevOperands[i] = op.Value
+	}
+
+	t.prevHasDest = false
+	if len(instr.Operands) > 0 {
+		switch {
+		case destOperand[instr.Opcode]:
+			t.prevDest = m.ReadMemory(t.prevIP + 1)
+			t.prevHasDest = true
+		case instr.Opcode == wmemOpcode:
+			raw := m.ReadMemory(t.prevIP + 1)
+			t.prevDest = raw
+			if vm.IsRegister(raw) {
+				t.prevDest = t.prevRegs[vm.RegisterIndex(raw)]
+			}
+			t.prevHasDest = true
+		}
+	}
+}
+
+func (t *Tracer) after(m *vm.Machine) {
+	var writes []Write
+
+	regs := m.Registers()
+	for i, v := range regs {
+		if v != t.prevRegs[i] {
+			writes = append(writes, Write{Kind: WriteRegister, Loc: uint16(i), Value: v})
+		}
+	}
+
+	if t.prevHasDest && (t.prevOpcode == wmemOpcode || vm.IsMemory(t.prevDest)) {
+		writes = append(writes, Write{Kind: WriteMemory, Loc: t.prevDest, Value: m.ReadMemory(t.prevDest)})
+	}
+
+	operands := t.prevOperands
+	if t.prevOpcode == callOpcode && len(operands) > 0 {
+		// call's operand may be a register; the address it actually jumped
+		// to is only known now, and is exactly what it set ip to.
+		operands = []uint16{m.IP()}
+	}
+
+	evt := Event{
+		IP:         t.prevIP,
+		Opcode:     t.prevOpcode,
+		Operands:   operands,
+		Writes:     writes,
+		StackDepth: m.StackDepth(),
+	}
+	// Encoding errors are not actionable here; the tracer is best-effort
+	// and must not abort the machine it is observing.
+	_ = evt.Encode(t.w)
+}
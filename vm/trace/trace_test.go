@@ -0,0 +1,88 @@
+package trace
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"testing"
+
+	"github.com/aneurysm9/challenger/vm"
+)
+
+// indirectCallProgram is:
+//
+//	0: set r0, 10   ; 1, 32768, 10
+//	3: call r0      ; 17, 32768
+//	5: halt         ; 0
+//	10: noop        ; 21
+//	11: ret         ; 18
+func indirectCallProgram() *vm.Machine {
+	m := vm.NewMachine()
+	words := map[uint16]uint16{
+		0: 1, 1: 1 << 15, 2: 10,
+		3: 17, 4: 1 << 15,
+		5:  0,
+		10: 21,
+		11: 18,
+	}
+	for addr, w := range words {
+		m.WriteMemory(addr, w)
+	}
+	return m
+}
+
+func TestTracerRecordsResolvedCallTarget(t *testing.T) {
+	m := indirectCallProgram()
+	var buf bytes.Buffer
+	Attach(m, &buf)
+
+	if err := m.Run(); err != vm.ErrorHalt {
+		t.Fatalf("Run: %v, want ErrorHalt", err)
+	}
+
+	var call *Event
+	br := bufio.NewReader(&buf)
+	for {
+		evt, err := DecodeEvent(br)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("DecodeEvent: %v", err)
+		}
+		if evt.Opcode == callOpcode {
+			e := evt
+			call = &e
+		}
+	}
+
+	if call == nil {
+		t.Fatal("no call event recorded")
+	}
+	// Regression: the traced operand used to be the raw register-indirect
+	// operand (0, the register number), not the address call() actually
+	// jumped to.
+	if len(call.Operands) != 1 || call.Operands[0] != 10 {
+		t.Errorf("call.Operands = %v, want [10] (resolved jump target)", call.Operands)
+	}
+}
+
+func TestAnalyzeBuildsCallEdgeForIndirectCall(t *testing.T) {
+	m := indirectCallProgram()
+	var buf bytes.Buffer
+	Attach(m, &buf)
+
+	if err := m.Run(); err != vm.ErrorHalt {
+		t.Fatalf("Run: %v, want ErrorHalt", err)
+	}
+
+	stats, err := Analyze(&buf)
+	if err != nil {
+		t.Fatalf("Analyze: %v", err)
+	}
+
+	want := CallEdge{From: 3, To: 10}
+	if stats.CallEdges[want] != 1 {
+		t.Errorf("CallEdges[%+v] = %d, want 1 (got %+v)", want, stats.CallEdges[want], stats.CallEdges)
+	}
+}
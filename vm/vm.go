@@ -2,6 +2,7 @@ package vm
 
 import (
 	"fmt"
+	"io"
 	"io/ioutil"
 	"os"
 
@@ -25,13 +26,32 @@ type Machine struct {
 	memory    []uint16
 	registers [8]uint16
 	stack     *stck.Stack
+	callStack []uint16
 	ip        uint16
 	Debug     bool
+
+	// In is read for the in instruction. Defaults to os.Stdin.
+	In io.Reader
+	// Out is written for the out instruction. Defaults to os.Stdout.
+	Out io.Writer
+
+	// PreStep, if set, is invoked before every instruction is executed. If
+	// it returns an error, Run stops and returns that error without
+	// executing the instruction.
+	PreStep func(m *Machine) error
+	// PostStep, if set, is invoked after every instruction is executed.
+	PostStep func(m *Machine)
 }
 
 // NewMachine creates a new Machine instance
 func NewMachine() *Machine {
-	return &Machine{memory: make([]uint16, 1<<15), stack: stck.New(), Debug: false}
+	return &Machine{
+		memory: make([]uint16, 1<<15),
+		stack:  stck.New(),
+		Debug:  false,
+		In:     os.Stdin,
+		Out:    os.Stdout,
+	}
 }
 
 // LoadImage loads a machine memory image into a new machine
@@ -58,10 +78,31 @@ func LoadImage(fn string) (*Machine, error) {
 // Run starts a Machine
 func (m *Machine) Run() error {
 	for {
-		if err := m.next(); err != nil {
+		if err := m.Step(); err != nil {
+			return err
+		}
+	}
+}
+
+// Step executes a single instruction, consulting PreStep and PostStep if
+// they are set. It is the building block Run uses internally, and is also
+// suitable for driving the machine from an external step controller such as
+// a debugger.
+func (m *Machine) Step() error {
+	if m.PreStep != nil {
+		if err := m.PreStep(m); err != nil {
 			return err
 		}
 	}
+
+	if err := m.next(); err != nil {
+		return err
+	}
+
+	if m.PostStep != nil {
+		m.PostStep(m)
+	}
+	return nil
 }
 
 func (m *Machine) next() error {
@@ -432,6 +473,7 @@ func (m *Machine) call() error {
 	}
 
 	m.stack.Push(m.ip + 2)
+	m.callStack = append(m.callStack, dest)
 	m.ip = dest
 	return nil
 }
@@ -454,6 +496,10 @@ func (m *Machine) ret() error {
 		}).Debug("Returning from routine")
 	}
 
+	if len(m.callStack) > 0 {
+		m.callStack = m.callStack[:len(m.callStack)-1]
+	}
+
 	m.ip = dest
 	return nil
 }
@@ -467,7 +513,7 @@ func (m *Machine) out() error {
 			"val": val,
 		}).Debug("Printing character")
 	}
-	fmt.Printf("%c", val)
+	fmt.Fprintf(m.Out, "%c", val)
 	m.ip += 2
 	return nil
 }
@@ -475,7 +521,9 @@ func (m *Machine) out() error {
 func (m *Machine) in() error {
 	dest := m.memory[m.ip+1]
 	b := make([]byte, 1)
-	os.Stdin.Read(b)
+	if _, err := m.In.Read(b); err != nil {
+		return err
+	}
 	if uint16(b[0]) != 13 {
 		// Skip CR because windows is stupid
 		m.setVal(dest, uint16(b[0]))
@@ -525,3 +573,85 @@ func isMem(v uint16) bool {
 func isReg(v uint16) bool {
 	return v >= maxSize
 }
+
+// IsRegister reports whether v addresses a register rather than a memory
+// location.
+func IsRegister(v uint16) bool {
+	return isReg(v)
+}
+
+// IsMemory reports whether v addresses a memory location rather than a
+// register.
+func IsMemory(v uint16) bool {
+	return isMem(v)
+}
+
+// RegisterIndex returns the register number a register-valued word refers
+// to. The caller should check IsRegister first.
+func RegisterIndex(v uint16) uint16 {
+	return v % maxSize
+}
+
+// IP returns the machine's current instruction pointer.
+func (m *Machine) IP() uint16 {
+	return m.ip
+}
+
+// SetIP sets the machine's instruction pointer, e.g. to resume execution at
+// a patched address.
+func (m *Machine) SetIP(ip uint16) {
+	m.ip = ip
+}
+
+// Registers returns a copy of the machine's register file.
+func (m *Machine) Registers() [8]uint16 {
+	return m.registers
+}
+
+// Register returns the value of register idx, which must be in [0,8).
+func (m *Machine) Register(idx uint16) (uint16, error) {
+	if idx > 7 {
+		return 0, fmt.Errorf("invalid register %d", idx)
+	}
+	return m.registers[idx], nil
+}
+
+// SetRegister sets register idx, which must be in [0,8).
+func (m *Machine) SetRegister(idx, val uint16) error {
+	if idx > 7 {
+		return fmt.Errorf("invalid register %d", idx)
+	}
+	m.registers[idx] = val
+	return nil
+}
+
+// MemorySize returns the number of addressable memory words.
+func (m *Machine) MemorySize() int {
+	return len(m.memory)
+}
+
+// ReadMemory returns the value stored at addr.
+func (m *Machine) ReadMemory(addr uint16) uint16 {
+	return m.memory[addr]
+}
+
+// WriteMemory sets the value stored at addr, e.g. to patch out a puzzle
+// check from the debugger.
+func (m *Machine) WriteMemory(addr, val uint16) {
+	m.memory[addr] = val
+}
+
+// StackDepth returns the number of values currently on the machine's data
+// stack.
+func (m *Machine) StackDepth() int {
+	return m.stack.Len()
+}
+
+// CallStack returns the addresses of the routines currently on the call
+// stack, outermost first. It is tracked separately from the machine's data
+// stack, which also carries values pushed by the running program.
+func (m *Machine) CallStack() []uint16 {
+	frames := make([]uint16, len(m.callStack))
+	copy(frames, m.callStack)
+	return frames
+}